@@ -0,0 +1,118 @@
+package cloudflare
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationErrorType classifies the kind of problem a ValidationError
+// describes, mirroring the small, fixed vocabulary used by Kubernetes'
+// apimachinery/pkg/util/validation/field package.
+type ValidationErrorType string
+
+const (
+	// ErrorTypeInvalid indicates a value was set but is malformed.
+	ErrorTypeInvalid ValidationErrorType = "Invalid"
+	// ErrorTypeRequired indicates a required field was not set.
+	ErrorTypeRequired ValidationErrorType = "Required"
+	// ErrorTypeNotSupported indicates a value isn't one of the accepted values.
+	ErrorTypeNotSupported ValidationErrorType = "NotSupported"
+	// ErrorTypeTooLong indicates a value exceeds a maximum length.
+	ErrorTypeTooLong ValidationErrorType = "TooLong"
+)
+
+// ValidationError is a single validation failure against a specific,
+// dotted field path, e.g. "record.data.priority".
+type ValidationError struct {
+	Type     ValidationErrorType
+	Path     string
+	BadValue interface{}
+	Detail   string
+}
+
+// Error implements the error interface so a *ValidationError can be used
+// anywhere Terraform's schema layer expects a plain error.
+func (e *ValidationError) Error() string {
+	msg := fmt.Sprintf("%s: %s", e.Path, e.Type)
+	if e.BadValue != nil {
+		msg += fmt.Sprintf(" %q", e.BadValue)
+	}
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+// Invalid returns a ValidationError indicating the value at path is set but malformed.
+func Invalid(path string, value interface{}, detail string) *ValidationError {
+	return &ValidationError{Type: ErrorTypeInvalid, Path: path, BadValue: value, Detail: detail}
+}
+
+// Required returns a ValidationError indicating path must be set.
+func Required(path string) *ValidationError {
+	return &ValidationError{Type: ErrorTypeRequired, Path: path, Detail: "must be set"}
+}
+
+// NotSupported returns a ValidationError indicating the value at path isn't one of valid.
+func NotSupported(path string, value interface{}, valid []string) *ValidationError {
+	detail := ""
+	if len(valid) > 0 {
+		detail = fmt.Sprintf("supported values: %s", strings.Join(valid, ", "))
+	}
+	return &ValidationError{Type: ErrorTypeNotSupported, Path: path, BadValue: value, Detail: detail}
+}
+
+// TooLong returns a ValidationError indicating the value at path exceeds maxLength.
+func TooLong(path string, value interface{}, maxLength int) *ValidationError {
+	return &ValidationError{
+		Type:     ErrorTypeTooLong,
+		Path:     path,
+		BadValue: value,
+		Detail:   fmt.Sprintf("must be no more than %d characters", maxLength),
+	}
+}
+
+// ValidationErrorList aggregates the ValidationErrors produced by one or
+// more, possibly nested, validators so every problem found in a pass over
+// a resource can be reported at once instead of one at a time.
+type ValidationErrorList []*ValidationError
+
+// Append adds errs to the list, ignoring nils so callers can append the
+// (possibly nil) result of an `if err := ...; err != nil` check unconditionally.
+func (list ValidationErrorList) Append(errs ...*ValidationError) ValidationErrorList {
+	for _, err := range errs {
+		if err != nil {
+			list = append(list, err)
+		}
+	}
+	return list
+}
+
+// Extend concatenates another ValidationErrorList onto list, for composing
+// the results of sub-validators (e.g. an SRV record built from priority,
+// weight and port sub-validators) into one.
+func (list ValidationErrorList) Extend(other ValidationErrorList) ValidationErrorList {
+	return append(list, other...)
+}
+
+// Prefix rewrites every path in the list to be rooted under path, for use
+// when folding a sub-validator's results into a parent field's path.
+func (list ValidationErrorList) Prefix(path string) ValidationErrorList {
+	for _, err := range list {
+		err.Path = path + "." + err.Path
+	}
+	return list
+}
+
+// ToErrors flattens the list into the []error slice Terraform's
+// schema.SchemaValidateFunc is expected to return.
+func (list ValidationErrorList) ToErrors() []error {
+	if len(list) == 0 {
+		return nil
+	}
+	errs := make([]error, len(list))
+	for i, err := range list {
+		errs[i] = err
+	}
+	return errs
+}
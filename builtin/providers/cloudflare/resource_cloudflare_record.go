@@ -0,0 +1,198 @@
+package cloudflare
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/cloudflare/cloudflare-go"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceCloudflareRecord returns the cloudflare_record resource. Its
+// "type"/"name"/"data" validation runs through validateRecordType,
+// validateRecordName and validateRecordData so malformed records are
+// rejected by Terraform before they ever reach the Cloudflare API.
+func resourceCloudflareRecord() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudflareRecordCreate,
+		Read:   resourceCloudflareRecordRead,
+		Update: resourceCloudflareRecordUpdate,
+		Delete: resourceCloudflareRecordDelete,
+
+		Schema: map[string]*schema.Schema{
+			"zone_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateRecordType,
+			},
+			"content": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"ttl": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1, // Cloudflare's "automatic" TTL
+				ValidateFunc: validateTTL,
+			},
+			"proxied": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			// data holds the structured content of record types Cloudflare
+			// doesn't express as a flat `content` string - MX, SRV, CAA,
+			// TLSA and SSHFP. Its fields are the union of what each of
+			// those types needs; validateRecordData only looks at the
+			// fields its own record type requires.
+			"data": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"service":       {Type: schema.TypeString, Optional: true},
+						"proto":         {Type: schema.TypeString, Optional: true},
+						"priority":      {Type: schema.TypeInt, Optional: true},
+						"weight":        {Type: schema.TypeInt, Optional: true},
+						"port":          {Type: schema.TypeInt, Optional: true},
+						"target":        {Type: schema.TypeString, Optional: true},
+						"flags":         {Type: schema.TypeInt, Optional: true},
+						"tag":           {Type: schema.TypeString, Optional: true},
+						"value":         {Type: schema.TypeString, Optional: true},
+						"usage":         {Type: schema.TypeInt, Optional: true},
+						"selector":      {Type: schema.TypeInt, Optional: true},
+						"matching_type": {Type: schema.TypeInt, Optional: true},
+						"certificate":   {Type: schema.TypeString, Optional: true},
+						"algorithm":     {Type: schema.TypeInt, Optional: true},
+						"type":          {Type: schema.TypeInt, Optional: true},
+						"fingerprint":   {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+// recordData returns the single `data` block of d, and whether one was set.
+func recordData(d *schema.ResourceData) (map[string]interface{}, bool) {
+	blocks := d.Get("data").([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return nil, false
+	}
+	return blocks[0].(map[string]interface{}), true
+}
+
+// validateRecord runs the record's name and, where applicable, data
+// through their validators and folds the results into a single error.
+func validateRecord(d *schema.ResourceData) error {
+	recordType := d.Get("type").(string)
+	name := d.Get("name").(string)
+
+	errs := validateRecordName(recordType, name)
+	if data, ok := recordData(d); ok {
+		errs = errs.Extend(validateRecordData(recordType, data))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid %s record %q: %s", recordType, name, errs.ToErrors())
+	}
+
+	return nil
+}
+
+func resourceCloudflareRecordCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*cloudflare.API)
+
+	if err := validateRecord(d); err != nil {
+		return err
+	}
+
+	zoneID := d.Get("zone_id").(string)
+	newRecord := cloudflare.DNSRecord{
+		Type:    d.Get("type").(string),
+		Name:    d.Get("name").(string),
+		Content: d.Get("content").(string),
+		TTL:     d.Get("ttl").(int),
+		Proxied: d.Get("proxied").(bool),
+	}
+	if data, ok := recordData(d); ok {
+		newRecord.Data = data
+	}
+
+	log.Printf("[DEBUG] Cloudflare record create configuration: %#v", newRecord)
+
+	record, err := client.CreateDNSRecord(zoneID, newRecord)
+	if err != nil {
+		return fmt.Errorf("error creating DNS record for zone %q: %s", zoneID, err)
+	}
+
+	d.SetId(record.Result.ID)
+
+	return resourceCloudflareRecordRead(d, meta)
+}
+
+func resourceCloudflareRecordUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*cloudflare.API)
+
+	if err := validateRecord(d); err != nil {
+		return err
+	}
+
+	zoneID := d.Get("zone_id").(string)
+	updatedRecord := cloudflare.DNSRecord{
+		Type:    d.Get("type").(string),
+		Name:    d.Get("name").(string),
+		Content: d.Get("content").(string),
+		TTL:     d.Get("ttl").(int),
+		Proxied: d.Get("proxied").(bool),
+	}
+	if data, ok := recordData(d); ok {
+		updatedRecord.Data = data
+	}
+
+	if err := client.UpdateDNSRecord(zoneID, d.Id(), updatedRecord); err != nil {
+		return fmt.Errorf("error updating DNS record %q for zone %q: %s", d.Id(), zoneID, err)
+	}
+
+	return resourceCloudflareRecordRead(d, meta)
+}
+
+func resourceCloudflareRecordRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*cloudflare.API)
+	zoneID := d.Get("zone_id").(string)
+
+	record, err := client.DNSRecord(zoneID, d.Id())
+	if err != nil {
+		return fmt.Errorf("error reading DNS record %q for zone %q: %s", d.Id(), zoneID, err)
+	}
+
+	d.Set("name", record.Name)
+	d.Set("type", record.Type)
+	d.Set("content", record.Content)
+	d.Set("ttl", record.TTL)
+	d.Set("proxied", record.Proxied)
+
+	return nil
+}
+
+func resourceCloudflareRecordDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*cloudflare.API)
+	zoneID := d.Get("zone_id").(string)
+
+	if err := client.DeleteDNSRecord(zoneID, d.Id()); err != nil {
+		return fmt.Errorf("error deleting DNS record %q for zone %q: %s", d.Id(), zoneID, err)
+	}
+
+	return nil
+}
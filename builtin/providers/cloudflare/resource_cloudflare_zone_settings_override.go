@@ -0,0 +1,149 @@
+package cloudflare
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/cloudflare/cloudflare-go"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceCloudflareZoneSettingsOverride returns the
+// cloudflare_zone_settings_override resource. Its schema is synthesized
+// entirely from settingsRegistry, so a single resource lets users manage
+// every zone-level toggle Cloudflare exposes without a bespoke resource
+// per setting.
+func resourceCloudflareZoneSettingsOverride() *schema.Resource {
+	resourceSchema := map[string]*schema.Schema{
+		"zone_id": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+	}
+
+	for name, spec := range settingsRegistry {
+		resourceSchema[name] = schemaForSetting(name, spec)
+	}
+
+	return &schema.Resource{
+		Create: resourceCloudflareZoneSettingsOverrideUpdate,
+		Read:   resourceCloudflareZoneSettingsOverrideRead,
+		Update: resourceCloudflareZoneSettingsOverrideUpdate,
+		Delete: resourceCloudflareZoneSettingsOverrideDelete,
+
+		Schema: resourceSchema,
+	}
+}
+
+// zoneSettingsPatchPayload builds the body of a PATCH to
+// /zones/:id/settings from the registry settings that changed in d (or, on
+// first create, every setting present in config), keyed by the same
+// setting IDs Cloudflare's API uses.
+//
+// Every registry field is Optional+Computed, since Read populates it from
+// whatever Cloudflare already has. Sending every such field back on every
+// update would resend stale Computed values for settings the user never
+// configured, silently reverting changes made outside Terraform - so only
+// settings that actually changed (or are set at all on first create) are
+// included.
+func zoneSettingsPatchPayload(d *schema.ResourceData) []cloudflare.ZoneSetting {
+	items := make([]cloudflare.ZoneSetting, 0, len(settingsRegistry))
+
+	for name, spec := range settingsRegistry {
+		if !d.IsNewResource() && !d.HasChange(name) {
+			continue
+		}
+		if v, ok := d.GetOkExists(name); ok {
+			items = append(items, cloudflare.ZoneSetting{
+				ID:    spec.apiID(name),
+				Value: v,
+			})
+		}
+	}
+
+	return items
+}
+
+func resourceCloudflareZoneSettingsOverrideUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*cloudflare.API)
+	zoneID := d.Get("zone_id").(string)
+
+	payload := zoneSettingsPatchPayload(d)
+	log.Printf("[DEBUG] Cloudflare zone settings update for zone %s: %#v", zoneID, payload)
+
+	if _, err := client.UpdateZoneSettings(zoneID, payload); err != nil {
+		return fmt.Errorf("error updating zone settings for zone %q: %s", zoneID, err)
+	}
+
+	d.SetId(zoneID)
+
+	return resourceCloudflareZoneSettingsOverrideRead(d, meta)
+}
+
+func resourceCloudflareZoneSettingsOverrideRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*cloudflare.API)
+	zoneID := d.Id()
+
+	settings, err := client.ZoneSettings(zoneID)
+	if err != nil {
+		return fmt.Errorf("error reading zone settings for zone %q: %s", zoneID, err)
+	}
+
+	d.Set("zone_id", zoneID)
+
+	nameByAPIID := make(map[string]string, len(settingsRegistry))
+	for name, spec := range settingsRegistry {
+		nameByAPIID[spec.apiID(name)] = name
+	}
+
+	seen := make(map[string]bool, len(settings.Result))
+	for _, result := range settings.Result {
+		name, ok := nameByAPIID[result.ID]
+		if !ok {
+			continue
+		}
+		seen[name] = true
+
+		value := result.Value
+		if settingsRegistry[name].Kind == settingKindInt {
+			value = settingIntValue(value)
+		}
+		if err := d.Set(name, value); err != nil {
+			return fmt.Errorf("error setting %q for zone %q: %s", name, zoneID, err)
+		}
+	}
+
+	// Cloudflare's response omits settings a zone's plan doesn't support;
+	// fall back to the registry's documented default rather than leaving
+	// the attribute unset. minify and mobile_redirect have no registry
+	// default, so when omitted they are cleared.
+	for name, spec := range settingsRegistry {
+		if !seen[name] {
+			if err := d.Set(name, spec.Default); err != nil {
+				return fmt.Errorf("error setting %q for zone %q: %s", name, zoneID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// settingIntValue coerces a setting value decoded from Cloudflare's JSON
+// response (where all numbers decode as float64) into the int a
+// settingKindInt schema field expects.
+func settingIntValue(value interface{}) interface{} {
+	if f, ok := value.(float64); ok {
+		return int(f)
+	}
+	return value
+}
+
+func resourceCloudflareZoneSettingsOverrideDelete(d *schema.ResourceData, meta interface{}) error {
+	// Cloudflare has no concept of "deleting" a zone's settings: dropping
+	// this resource just stops Terraform managing overrides for the zone,
+	// it doesn't reset anything back to Cloudflare's defaults.
+	log.Printf("[INFO] Cloudflare zone settings override for zone %s removed from state; settings are left as-is", d.Id())
+	d.SetId("")
+	return nil
+}
@@ -1,55 +1,355 @@
 package cloudflare
 
 import (
+	"encoding/hex"
 	"fmt"
 	"net"
+	"net/mail"
+	"net/url"
+	"sort"
 	"strings"
 )
 
+// validRecordTypes are the DNS record types the cloudflare_record resource
+// accepts. Only some of them get per-type checks, in validateRecordName
+// (A, AAAA, CNAME, PTR, NS) and validateRecordData (MX, SRV, CAA, TLSA,
+// SSHFP); the content of the others is passed through unchecked.
+var validRecordTypes = map[string]struct{}{
+	"A":      {},
+	"AAAA":   {},
+	"CAA":    {},
+	"CERT":   {},
+	"CNAME":  {},
+	"DNSKEY": {},
+	"DS":     {},
+	"LOC":    {},
+	"MX":     {},
+	"NAPTR":  {},
+	"NS":     {},
+	"PTR":    {},
+	"SMIMEA": {},
+	"SPF":    {},
+	"SRV":    {},
+	"SSHFP":  {},
+	"TLSA":   {},
+	"TXT":    {},
+	"URI":    {},
+}
+
 // validateRecordType ensures that the cloudflare record type is valid
 func validateRecordType(v interface{}, k string) (ws []string, errors []error) {
 	value := v.(string)
 
-	validTypes := map[string]struct{}{
-		"A":     {},
-		"AAAA":  {},
-		"CNAME": {},
-		"TXT":   {},
-		"SRV":   {},
-		"LOC":   {},
-		"MX":    {},
-		"NS":    {},
-		"SPF":   {},
-	}
-
-	if _, ok := validTypes[value]; !ok {
-		errors = append(errors, fmt.Errorf(
-			`%q contains an invalid type %q. Valid types are "A", "AAAA", "CNAME", "TXT", "SRV", "LOC", "MX", "NS" or "SPF"`, k, value))
+	if _, ok := validRecordTypes[value]; !ok {
+		valid := make([]string, 0, len(validRecordTypes))
+		for t := range validRecordTypes {
+			valid = append(valid, t)
+		}
+		sort.Strings(valid)
+		errors = ValidationErrorList{}.Append(NotSupported(k, value, valid)).ToErrors()
 	}
 	return
 }
 
 // validateRecordName ensures that based on supplied record type, the name content matches
-// Currently only validates A and AAAA types
-func validateRecordName(t string, value string) error {
+func validateRecordName(t string, value string) ValidationErrorList {
+	list := ValidationErrorList{}
+
 	switch t {
 	case "A":
 		// Must be ipv4 addr
 		addr := net.ParseIP(value)
 		if addr == nil || !strings.Contains(value, ".") {
-			return fmt.Errorf("A record must be a valid IPv4 address, got: %q", value)
+			list = list.Append(Invalid("name", value, "A record must be a valid IPv4 address"))
 		}
 	case "AAAA":
 		// Must be ipv6 addr
 		addr := net.ParseIP(value)
 		if addr == nil || !strings.Contains(value, ":") {
-			return fmt.Errorf("AAAA record must be a valid IPv6 address, got: %q", value)
+			list = list.Append(Invalid("name", value, "AAAA record must be a valid IPv6 address"))
+		}
+	case "CNAME", "PTR", "NS":
+		if err := validateHostname("name", value); err != nil {
+			list = list.Append(err)
 		}
 	}
 
+	return list
+}
+
+// validateHostname ensures value is a syntactically valid DNS hostname: no
+// leading or trailing dot, only valid label characters (letters, digits,
+// hyphens and underscores - underscore labels like "_acme-challenge" and
+// "_domainconnect" are common in the wild and accepted by Cloudflare), and
+// no more than 253 characters overall.
+func validateHostname(path, value string) *ValidationError {
+	if value == "" {
+		return Required(path)
+	}
+	if len(value) > 253 {
+		return TooLong(path, value, 253)
+	}
+	if strings.HasPrefix(value, ".") || strings.HasSuffix(value, ".") {
+		return Invalid(path, value, "must not have a leading or trailing dot")
+	}
+
+	for _, label := range strings.Split(value, ".") {
+		if label == "" {
+			return Invalid(path, value, "contains an empty label")
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9') && r != '-' && r != '_' {
+				return Invalid(path, value, fmt.Sprintf("contains an invalid character %q", r))
+			}
+		}
+		if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return Invalid(path, value, fmt.Sprintf("label %q must not start or end with a hyphen", label))
+		}
+	}
+
+	return nil
+}
+
+// validateUint16 is a shared helper for the data-block fields (SRV's
+// priority/weight/port, CAA's flags, ...) that are documented as 16-bit or
+// narrower unsigned ranges.
+func validateUint16(path string, value, max int) *ValidationError {
+	if value < 0 || value > max {
+		return Invalid(path, value, fmt.Sprintf("must be between 0 and %d", max))
+	}
 	return nil
 }
 
+// validateSRVData ensures the `data` block of an SRV record carries all of
+// the fields Cloudflare requires to assemble the record, composing the
+// result from independent priority/weight/port sub-validators.
+func validateSRVData(data map[string]interface{}) ValidationErrorList {
+	list := ValidationErrorList{}
+
+	for _, key := range []string{"service", "proto", "priority", "weight", "port", "target"} {
+		if _, ok := data[key]; !ok {
+			list = list.Append(Required(key))
+		}
+	}
+
+	if priority, ok := data["priority"].(int); ok {
+		list = list.Append(validateUint16("priority", priority, 65535))
+	}
+	if weight, ok := data["weight"].(int); ok {
+		list = list.Append(validateUint16("weight", weight, 65535))
+	}
+	if port, ok := data["port"].(int); ok {
+		list = list.Append(validateUint16("port", port, 65535))
+	}
+
+	return list
+}
+
+// validCAATags are the tag values Cloudflare accepts for a CAA record.
+var validCAATags = map[string]struct{}{
+	"issue":     {},
+	"issuewild": {},
+	"iodef":     {},
+}
+
+// validateCAAData ensures the `data` block of a CAA record has sane flags,
+// a supported tag, and a value appropriate to that tag.
+func validateCAAData(data map[string]interface{}) ValidationErrorList {
+	list := ValidationErrorList{}
+
+	flags, ok := data["flags"].(int)
+	if !ok {
+		list = list.Append(Required("flags"))
+	} else if err := validateUint16("flags", flags, 255); err != nil {
+		list = list.Append(err)
+	}
+
+	tag, ok := data["tag"].(string)
+	if !ok {
+		list = list.Append(Required("tag"))
+		return list
+	}
+	if _, ok := validCAATags[tag]; !ok {
+		list = list.Append(NotSupported("tag", tag, []string{"issue", "issuewild", "iodef"}))
+		return list
+	}
+
+	value, ok := data["value"].(string)
+	if !ok || value == "" {
+		return list.Append(Required("value"))
+	}
+
+	switch tag {
+	case "issue", "issuewild":
+		// RFC 8659: issuer-domain-name *(";" parameter). Only the leading
+		// domain is a hostname; anything after the first ";" is CA-defined
+		// parameters (e.g. "letsencrypt.org; validationmethods=dns-01") and
+		// isn't ours to validate.
+		if value != ";" {
+			domain := value
+			if idx := strings.Index(value, ";"); idx != -1 {
+				domain = strings.TrimSpace(value[:idx])
+			}
+			if err := validateHostname("value", domain); err != nil {
+				list = list.Append(err)
+			}
+		}
+	case "iodef":
+		if strings.HasPrefix(value, "mailto:") {
+			if _, err := mail.ParseAddress(strings.TrimPrefix(value, "mailto:")); err != nil {
+				list = list.Append(Invalid("value", value, err.Error()))
+			}
+		} else {
+			u, err := url.Parse(value)
+			if err != nil || u.Scheme != "https" || u.Host == "" {
+				list = list.Append(Invalid("value", value, "must be a mailto: or https: URL"))
+			}
+		}
+	}
+
+	return list
+}
+
+// tlsaMatchingTypeLength maps a TLSA matching-type to the expected length,
+// in bytes, of its certificate association data.
+var tlsaMatchingTypeLength = map[int]int{
+	0: 0,  // full certificate, any length
+	1: 32, // SHA-256
+	2: 64, // SHA-512
+}
+
+// validateTLSAData ensures the `data` block of a TLSA record has usage,
+// selector and matching-type within their defined ranges, and hex
+// certificate association data of the length that matching type implies.
+func validateTLSAData(data map[string]interface{}) ValidationErrorList {
+	list := ValidationErrorList{}
+
+	if usage, ok := data["usage"].(int); !ok {
+		list = list.Append(Required("usage"))
+	} else if usage < 0 || usage > 3 {
+		list = list.Append(Invalid("usage", usage, "must be between 0 and 3"))
+	}
+
+	if selector, ok := data["selector"].(int); !ok {
+		list = list.Append(Required("selector"))
+	} else if selector < 0 || selector > 1 {
+		list = list.Append(Invalid("selector", selector, "must be 0 or 1"))
+	}
+
+	matchingType, ok := data["matching_type"].(int)
+	if !ok {
+		return list.Append(Required("matching_type"))
+	}
+	wantLen, known := tlsaMatchingTypeLength[matchingType]
+	if !known {
+		return list.Append(NotSupported("matching_type", matchingType, []string{"0", "1", "2"}))
+	}
+
+	certificate, ok := data["certificate"].(string)
+	if !ok {
+		return list.Append(Required("certificate"))
+	}
+	raw, err := hex.DecodeString(certificate)
+	if err != nil {
+		return list.Append(Invalid("certificate", certificate, "must be hex-encoded"))
+	}
+	if wantLen != 0 && len(raw) != wantLen {
+		list = list.Append(Invalid("certificate", certificate,
+			fmt.Sprintf("must be %d bytes for matching_type %d, got %d", wantLen, matchingType, len(raw))))
+	}
+
+	return list
+}
+
+// validSSHFPAlgorithms and validSSHFPTypes are the algorithm and
+// fingerprint-type values Cloudflare accepts for an SSHFP record.
+var (
+	validSSHFPAlgorithms = map[int]struct{}{1: {}, 2: {}, 3: {}, 4: {}}
+	validSSHFPTypes      = map[int]int{1: 40, 2: 64} // fp_type -> expected hex length
+)
+
+// validateSSHFPData ensures the `data` block of an SSHFP record has a
+// supported algorithm/fingerprint-type pair and a hex fingerprint of the
+// length that type implies.
+func validateSSHFPData(data map[string]interface{}) ValidationErrorList {
+	list := ValidationErrorList{}
+
+	algorithm, ok := data["algorithm"].(int)
+	if !ok {
+		list = list.Append(Required("algorithm"))
+	} else if _, ok := validSSHFPAlgorithms[algorithm]; !ok {
+		list = list.Append(NotSupported("algorithm", algorithm, []string{"1", "2", "3", "4"}))
+	}
+
+	fpType, ok := data["type"].(int)
+	if !ok {
+		return list.Append(Required("type"))
+	}
+	wantLen, known := validSSHFPTypes[fpType]
+	if !known {
+		return list.Append(NotSupported("type", fpType, []string{"1", "2"}))
+	}
+
+	fingerprint, ok := data["fingerprint"].(string)
+	if !ok {
+		return list.Append(Required("fingerprint"))
+	}
+	if len(fingerprint) != wantLen {
+		list = list.Append(Invalid("fingerprint", fingerprint,
+			fmt.Sprintf("must be %d hex characters for type %d, got %d", wantLen, fpType, len(fingerprint))))
+	} else if _, err := hex.DecodeString(fingerprint); err != nil {
+		list = list.Append(Invalid("fingerprint", fingerprint, "must be hex-encoded"))
+	}
+
+	return list
+}
+
+// validateMXData ensures the `data` block of an MX record has a priority
+// in the valid 16-bit range and a target that's a valid hostname.
+func validateMXData(data map[string]interface{}) ValidationErrorList {
+	list := ValidationErrorList{}
+
+	if priority, ok := data["priority"].(int); ok {
+		list = list.Append(validateUint16("priority", priority, 65535))
+	} else {
+		list = list.Append(Required("priority"))
+	}
+
+	if target, ok := data["target"].(string); ok {
+		if err := validateHostname("target", target); err != nil {
+			list = list.Append(err)
+		}
+	} else {
+		list = list.Append(Required("target"))
+	}
+
+	return list
+}
+
+// validateRecordData dispatches to the per-type validator for record types
+// whose content is a structured `data` block rather than a flat `content`
+// string, so malformed records are rejected before they reach the
+// Cloudflare API. Results are rooted under "data" so a caller folding this
+// into a wider record validation gets paths like "record.data.priority".
+func validateRecordData(t string, data map[string]interface{}) ValidationErrorList {
+	var list ValidationErrorList
+
+	switch t {
+	case "MX":
+		list = validateMXData(data)
+	case "SRV":
+		list = validateSRVData(data)
+	case "CAA":
+		list = validateCAAData(data)
+	case "TLSA":
+		list = validateTLSAData(data)
+	case "SSHFP":
+		list = validateSSHFPData(data)
+	}
+
+	return list.Prefix("data")
+}
+
 func validatePageRuleStatus(v interface{}, k string) (ws []string, errors []error) {
 	value := v.(string)
 
@@ -59,32 +359,37 @@ func validatePageRuleStatus(v interface{}, k string) (ws []string, errors []erro
 	}
 
 	if _, ok := validStatuses[value]; !ok {
-		errors = append(errors, fmt.Errorf(
-			`%q contains an invalid status %q. Valid statuses are "active" or "paused"`, k, value))
+		errors = ValidationErrorList{}.Append(NotSupported(k, value, []string{"active", "paused"})).ToErrors()
 	}
 	return
 }
 
-func assertIsOneOf(setting string, acceptables []interface{}, value interface{}) error {
+// assertIsOneOf returns a ValidationError if value isn't one of acceptables.
+func assertIsOneOf(path string, acceptables []interface{}, value interface{}) *ValidationError {
 	for _, acceptable := range acceptables {
 		if value == acceptable {
 			return nil
 		}
 	}
-	return fmt.Errorf("%q %q invalid: must be one of %q", setting, value, acceptables)
+
+	valid := make([]string, len(acceptables))
+	for i, acceptable := range acceptables {
+		valid[i] = fmt.Sprintf("%v", acceptable)
+	}
+	return NotSupported(path, value, valid)
 }
 
 func validateCacheLevel(v interface{}, k string) (ws []string, errors []error) {
-	if err := assertIsOneOf("Cache level", []interface{}{"bypass", "basic", "simplified", "aggressive", "cache_everything"}, v.(string)); err != nil {
-		errors = append(errors, err)
-	}
+	errors = ValidationErrorList{}.Append(
+		assertIsOneOf(k, []interface{}{"bypass", "basic", "simplified", "aggressive", "cache_everything"}, v.(string)),
+	).ToErrors()
 	return
 }
 
 func validateForwardStatusCode(v interface{}, k string) (ws []string, errors []error) {
-	if err := assertIsOneOf("Fowarding status code", []interface{}{301, 302}, v.(int)); err != nil {
-		errors = append(errors, err)
-	}
+	errors = ValidationErrorList{}.Append(
+		assertIsOneOf(k, []interface{}{301, 302}, v.(int)),
+	).ToErrors()
 	return
 }
 
@@ -92,42 +397,46 @@ func validateIsTrue(v interface{}, k string) (ws []string, errors []error) {
 	if !v.(bool) {
 		// We can't just ignore this, since if the action is *not set* by the
 		// user it will appear as `false` too.
-		errors = append(errors, fmt.Errorf("Action %q has no further setting; `true` is the only valid option.", k))
+		errors = ValidationErrorList{}.Append(
+			Invalid(k, v, "has no further setting; `true` is the only valid option"),
+		).ToErrors()
 	}
 	return
 }
 
 func validateOnOff(v interface{}, k string) (ws []string, errors []error) {
-	if err := assertIsOneOf(k, []interface{}{"on", "off"}, v.(string)); err != nil {
-		errors = append(errors, err)
-	}
+	errors = ValidationErrorList{}.Append(
+		assertIsOneOf(k, []interface{}{"on", "off"}, v.(string)),
+	).ToErrors()
 	return
 }
 
 func validateRocketLoader(v interface{}, k string) (ws []string, errors []error) {
-	if err := assertIsOneOf("Rocket loader", []interface{}{"off", "manual", "automatic"}, v.(string)); err != nil {
-		errors = append(errors, err)
-	}
+	errors = ValidationErrorList{}.Append(
+		assertIsOneOf(k, []interface{}{"off", "manual", "automatic"}, v.(string)),
+	).ToErrors()
 	return
 }
 
 func validateSecurityLevel(v interface{}, k string) (ws []string, errors []error) {
-	if err := assertIsOneOf("Security level", []interface{}{"essentially_off", "low", "medium", "high", "under_attack"}, v.(string)); err != nil {
-		errors = append(errors, err)
-	}
+	errors = ValidationErrorList{}.Append(
+		assertIsOneOf(k, []interface{}{"essentially_off", "low", "medium", "high", "under_attack"}, v.(string)),
+	).ToErrors()
 	return
 }
 
 func validateSSL(v interface{}, k string) (ws []string, errors []error) {
-	if err := assertIsOneOf("SSL mode", []interface{}{"off", "flexible", "full", "strict"}, v.(string)); err != nil {
-		errors = append(errors, err)
-	}
+	errors = ValidationErrorList{}.Append(
+		assertIsOneOf(k, []interface{}{"off", "flexible", "full", "strict"}, v.(string)),
+	).ToErrors()
 	return
 }
 
 func validateTTL(v interface{}, k string) (ws []string, errors []error) {
 	if ttl, maxTTL := v.(int), 31536000; ttl > maxTTL {
-		errors = append(errors, fmt.Errorf("Cache TTL of %q too long: max value is %q", ttl, maxTTL))
+		errors = ValidationErrorList{}.Append(
+			Invalid(k, ttl, fmt.Sprintf("too long: max value is %d", maxTTL)),
+		).ToErrors()
 	}
 	return
 }
@@ -0,0 +1,174 @@
+package cloudflare
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// settingValueKind is the shape of value a zone setting's API field takes,
+// letting settingsRegistry describe every setting declaratively instead of
+// through a bespoke validator and schema field for each one.
+type settingValueKind int
+
+const (
+	// settingKindOnOff is a string setting restricted to "on"/"off", the
+	// shape most Cloudflare zone settings take.
+	settingKindOnOff settingValueKind = iota
+	// settingKindEnum is a string setting with a larger, setting-specific
+	// vocabulary (e.g. ssl's "off"/"flexible"/"full"/"strict").
+	settingKindEnum
+	// settingKindInt is an integer setting bounded by Min/Max (e.g. TTLs).
+	settingKindInt
+	// settingKindMap is a setting whose value is a nested object rather
+	// than a scalar (e.g. minify's {css,html,js}, mobile_redirect's
+	// {status,mobile_subdomain,strip_uri}). Cloudflare defines the shape
+	// of these per setting, so they carry no Enum/Min/Max of their own.
+	settingKindMap
+)
+
+// SettingSpec describes one zone-level setting exposed by Cloudflare's
+// /zones/:id/settings API: its value type, the values or range it accepts,
+// and the default Cloudflare applies before it's ever been overridden.
+// cloudflare_zone_settings_override iterates settingsRegistry to synthesize
+// its schema, validators and PATCH payload from these specs.
+type SettingSpec struct {
+	Kind    settingValueKind
+	Enum    []string // valid values, for settingKindEnum
+	Min     int      // inclusive lower bound, for settingKindInt
+	Max     int      // inclusive upper bound, for settingKindInt
+	Default interface{}
+	// APIID is the setting ID Cloudflare's API uses, when it differs from
+	// the registry key. The registry is keyed by the Terraform attribute
+	// name, which must be a valid HCL identifier - "0rtt" isn't, so it's
+	// registered as "zero_rtt" with APIID "0rtt". Leave empty when the
+	// attribute name and API ID match.
+	APIID string
+}
+
+// apiID returns the setting ID Cloudflare's API uses for the registry
+// entry named name.
+func (spec SettingSpec) apiID(name string) string {
+	if spec.APIID != "" {
+		return spec.APIID
+	}
+	return name
+}
+
+// settingsRegistry enumerates every zone-level setting Cloudflare exposes
+// through /zones/:id/settings. Adding a new setting Cloudflare introduces
+// is a single entry here, rather than a new validator function, a new
+// schema field and a new case in the PATCH payload builder.
+var settingsRegistry = map[string]SettingSpec{
+	"ssl":                         {Kind: settingKindEnum, Enum: []string{"off", "flexible", "full", "strict"}, Default: "flexible"},
+	"always_use_https":            {Kind: settingKindOnOff, Default: "off"},
+	"min_tls_version":             {Kind: settingKindEnum, Enum: []string{"1.0", "1.1", "1.2", "1.3"}, Default: "1.0"},
+	"tls_1_3":                     {Kind: settingKindOnOff, Default: "off"},
+	"opportunistic_encryption":    {Kind: settingKindOnOff, Default: "on"},
+	"automatic_https_rewrites":    {Kind: settingKindOnOff, Default: "off"},
+	"http2":                       {Kind: settingKindOnOff, Default: "on"},
+	"http3":                       {Kind: settingKindOnOff, Default: "off"},
+	"zero_rtt":                    {Kind: settingKindOnOff, Default: "off", APIID: "0rtt"},
+	"brotli":                      {Kind: settingKindOnOff, Default: "off"},
+	"minify":                      {Kind: settingKindMap},
+	"mobile_redirect":             {Kind: settingKindMap},
+	"polish":                      {Kind: settingKindEnum, Enum: []string{"off", "lossless", "lossy"}, Default: "off"},
+	"webp":                        {Kind: settingKindOnOff, Default: "off"},
+	"image_resizing":              {Kind: settingKindOnOff, Default: "off"},
+	"hotlink_protection":          {Kind: settingKindOnOff, Default: "off"},
+	"email_obfuscation":           {Kind: settingKindOnOff, Default: "on"},
+	"server_side_exclude":         {Kind: settingKindOnOff, Default: "on"},
+	"browser_check":               {Kind: settingKindOnOff, Default: "on"},
+	"challenge_ttl":               {Kind: settingKindInt, Min: 300, Max: 31536000, Default: 1800},
+	"security_level":              {Kind: settingKindEnum, Enum: []string{"essentially_off", "low", "medium", "high", "under_attack"}, Default: "medium"},
+	"waf":                         {Kind: settingKindOnOff, Default: "off"},
+	"advanced_ddos":               {Kind: settingKindOnOff, Default: "on"},
+	"ip_geolocation":              {Kind: settingKindOnOff, Default: "on"},
+	"ipv6":                        {Kind: settingKindOnOff, Default: "off"},
+	"websockets":                  {Kind: settingKindOnOff, Default: "off"},
+	"pseudo_ipv4":                 {Kind: settingKindEnum, Enum: []string{"off", "add_header", "overwrite_header"}, Default: "off"},
+	"prefetch_preload":            {Kind: settingKindOnOff, Default: "off"},
+	"response_buffering":          {Kind: settingKindOnOff, Default: "off"},
+	"true_client_ip_header":       {Kind: settingKindOnOff, Default: "off"},
+	"development_mode":            {Kind: settingKindOnOff, Default: "off"},
+	"cache_level":                 {Kind: settingKindEnum, Enum: []string{"bypass", "basic", "simplified", "aggressive", "cache_everything"}, Default: "aggressive"},
+	"browser_cache_ttl":           {Kind: settingKindInt, Min: 0, Max: 31536000, Default: 14400},
+	"edge_cache_ttl":              {Kind: settingKindInt, Min: 30, Max: 31536000, Default: 7200},
+	"sort_query_string_for_cache": {Kind: settingKindOnOff, Default: "off"},
+	"rocket_loader":               {Kind: settingKindEnum, Enum: []string{"off", "manual", "automatic"}, Default: "off"},
+	"mirage":                      {Kind: settingKindOnOff, Default: "off"},
+}
+
+// settingValidatorOverrides reuses the existing named validators from
+// validators.go for settings whose enum they already cover, rather than
+// re-deriving an equivalent validator from the registry's Enum list and
+// ending up maintaining the same vocabulary in two places.
+var settingValidatorOverrides = map[string]schema.SchemaValidateFunc{
+	"ssl":            validateSSL,
+	"cache_level":    validateCacheLevel,
+	"security_level": validateSecurityLevel,
+	"rocket_loader":  validateRocketLoader,
+}
+
+// schemaForSetting synthesizes the schema.Schema for one zone setting from
+// its SettingSpec.
+func schemaForSetting(name string, spec SettingSpec) *schema.Schema {
+	s := &schema.Schema{
+		Optional: true,
+		Computed: true,
+	}
+
+	switch spec.Kind {
+	case settingKindOnOff:
+		s.Type = schema.TypeString
+		s.ValidateFunc = validateOnOff
+	case settingKindEnum:
+		s.Type = schema.TypeString
+		if override, ok := settingValidatorOverrides[name]; ok {
+			s.ValidateFunc = override
+		} else {
+			s.ValidateFunc = validateSettingEnum(spec.Enum)
+		}
+	case settingKindInt:
+		s.Type = schema.TypeInt
+		s.ValidateFunc = validateSettingRange(spec.Min, spec.Max)
+	case settingKindMap:
+		// The nested shape (minify's css/html/js, mobile_redirect's
+		// status/mobile_subdomain/strip_uri, ...) is Cloudflare's to define
+		// and validate; Terraform just passes the map through.
+		s.Type = schema.TypeMap
+		s.Elem = &schema.Schema{Type: schema.TypeString}
+	}
+
+	return s
+}
+
+// validateSettingEnum returns a schema.SchemaValidateFunc asserting the
+// setting's value is one of valid, reusing the same ValidationErrorList
+// path the other Cloudflare validators build on. Used for registry
+// settings that have no existing dedicated validator to reuse.
+func validateSettingEnum(valid []string) schema.SchemaValidateFunc {
+	acceptable := make([]interface{}, len(valid))
+	for i, v := range valid {
+		acceptable[i] = v
+	}
+
+	return func(v interface{}, k string) (ws []string, errors []error) {
+		errors = ValidationErrorList{}.Append(assertIsOneOf(k, acceptable, v.(string))).ToErrors()
+		return
+	}
+}
+
+// validateSettingRange returns a schema.SchemaValidateFunc asserting the
+// setting's integer value falls within [min, max].
+func validateSettingRange(min, max int) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (ws []string, errors []error) {
+		value := v.(int)
+		if value < min || value > max {
+			errors = ValidationErrorList{}.Append(
+				Invalid(k, value, fmt.Sprintf("must be between %d and %d", min, max)),
+			).ToErrors()
+		}
+		return
+	}
+}